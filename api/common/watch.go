@@ -5,7 +5,11 @@ package common
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/juju/errors"
+	"github.com/juju/worker/v2/catacomb"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api/base"
@@ -14,6 +18,10 @@ import (
 	"github.com/juju/juju/watcher"
 )
 
+// newNotifyWatcher is indirected so tests can substitute a fake watcher
+// constructor instead of one that makes real API calls.
+var newNotifyWatcher = apiwatcher.NewNotifyWatcher
+
 // Watch starts a NotifyWatcher for the entity with the specified tag.
 func Watch(facade base.FacadeCaller, method string, tag names.Tag) (watcher.NotifyWatcher, error) {
 	var results params.NotifyWatchResults
@@ -31,5 +39,155 @@ func Watch(facade base.FacadeCaller, method string, tag names.Tag) (watcher.Noti
 	if result.Error != nil {
 		return nil, result.Error
 	}
-	return apiwatcher.NewNotifyWatcher(facade.RawAPICaller(), result), nil
+	return newNotifyWatcher(facade.RawAPICaller(), result), nil
+}
+
+// TaggedError pairs a tag with the error that occurred while starting a
+// watcher for it.
+type TaggedError struct {
+	Tag names.Tag
+	Err error
+}
+
+// MultiWatcherError reports the per-tag failures that occurred when
+// WatchMany asked the facade to start watchers for several entities at
+// once. Tags not listed here got a usable watcher back from WatchMany;
+// only the listed tags failed.
+type MultiWatcherError struct {
+	Total  int
+	Errors []TaggedError
+}
+
+func (e *MultiWatcherError) Error() string {
+	parts := make([]string, len(e.Errors))
+	for i, te := range e.Errors {
+		parts[i] = fmt.Sprintf("%s: %s", te.Tag, te.Err)
+	}
+	return fmt.Sprintf("failed to watch %d of %d entities: %s", len(e.Errors), e.Total, strings.Join(parts, "; "))
+}
+
+// WatchMany starts a NotifyWatcher for each of tags in a single facade
+// call, returning the resulting watchers aligned with tags by index.
+//
+// Unlike Watch, a single failure doesn't abort the whole request: tags
+// that the facade reports an error for get a nil entry in the returned
+// slice, and every such failure is collected into a *MultiWatcherError
+// returned alongside the (otherwise usable) watchers.
+func WatchMany(facade base.FacadeCaller, method string, tags []names.Tag) ([]watcher.NotifyWatcher, error) {
+	entities := make([]params.Entity, len(tags))
+	for i, tag := range tags {
+		entities[i] = params.Entity{Tag: tag.String()}
+	}
+
+	var results params.NotifyWatchResults
+	args := params.Entities{Entities: entities}
+	if err := facade.FacadeCall(method, args, &results); err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(tags) {
+		return nil, fmt.Errorf("expected %d results, got %d", len(tags), len(results.Results))
+	}
+
+	watchers := make([]watcher.NotifyWatcher, len(tags))
+	multiErr := MultiWatcherError{Total: len(tags)}
+	for i, result := range results.Results {
+		if result.Error != nil {
+			multiErr.Errors = append(multiErr.Errors, TaggedError{Tag: tags[i], Err: result.Error})
+			continue
+		}
+		watchers[i] = newNotifyWatcher(facade.RawAPICaller(), result)
+	}
+	if len(multiErr.Errors) > 0 {
+		return watchers, &multiErr
+	}
+	return watchers, nil
+}
+
+// aggregatedWatcher fans the Changes channels of several NotifyWatchers
+// into a single channel, coalescing ticks that land within the same
+// debounce window.
+type aggregatedWatcher struct {
+	catacomb catacomb.Catacomb
+	watchers []watcher.NotifyWatcher
+	debounce time.Duration
+	out      chan struct{}
+}
+
+// WatchAggregated combines watchers into a single NotifyWatcher that ticks
+// at most once per debounce window, however many of the underlying
+// watchers fired during that window. Killing the returned watcher kills
+// every watcher in watchers.
+func WatchAggregated(debounce time.Duration, watchers []watcher.NotifyWatcher) (watcher.NotifyWatcher, error) {
+	w := &aggregatedWatcher{
+		watchers: watchers,
+		debounce: debounce,
+		out:      make(chan struct{}),
+	}
+	if err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// Changes is part of the watcher.NotifyWatcher interface.
+func (w *aggregatedWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+// Kill is part of the watcher.NotifyWatcher interface.
+func (w *aggregatedWatcher) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the watcher.NotifyWatcher interface.
+func (w *aggregatedWatcher) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *aggregatedWatcher) loop() error {
+	dirty := make(chan struct{}, 1)
+	for _, watch := range w.watchers {
+		if err := w.catacomb.Add(watch); err != nil {
+			return errors.Trace(err)
+		}
+		watch := watch
+		go func() {
+			for {
+				select {
+				case <-w.catacomb.Dying():
+					return
+				case _, ok := <-watch.Changes():
+					if !ok {
+						return
+					}
+					select {
+					case dirty <- struct{}{}:
+					default:
+					}
+				}
+			}
+		}()
+	}
+
+	var pending <-chan time.Time
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case <-dirty:
+			if pending == nil {
+				pending = time.After(w.debounce)
+			}
+		case <-pending:
+			pending = nil
+			select {
+			case w.out <- struct{}{}:
+			case <-w.catacomb.Dying():
+				return w.catacomb.ErrDying()
+			}
+		}
+	}
 }
@@ -0,0 +1,204 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/watcher"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+// fakeNotifyWatcher is a minimal watcher.NotifyWatcher whose lifecycle
+// tests can drive directly, without any real API connection.
+type fakeNotifyWatcher struct {
+	changes chan struct{}
+	killed  chan struct{}
+	err     error
+}
+
+func newFakeNotifyWatcher() *fakeNotifyWatcher {
+	return &fakeNotifyWatcher{
+		changes: make(chan struct{}, 1),
+		killed:  make(chan struct{}),
+	}
+}
+
+func (w *fakeNotifyWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+func (w *fakeNotifyWatcher) Kill() {
+	select {
+	case <-w.killed:
+	default:
+		close(w.killed)
+	}
+}
+
+func (w *fakeNotifyWatcher) Wait() error {
+	<-w.killed
+	return w.err
+}
+
+// fakeFacadeCaller is a minimal base.FacadeCaller that returns canned
+// results instead of making a real API call.
+type fakeFacadeCaller struct {
+	results params.NotifyWatchResults
+	err     error
+}
+
+func (f *fakeFacadeCaller) FacadeCall(request string, args, response interface{}) error {
+	if f.err != nil {
+		return f.err
+	}
+	out := response.(*params.NotifyWatchResults)
+	*out = f.results
+	return nil
+}
+
+func (f *fakeFacadeCaller) Name() string                { return "FakeFacade" }
+func (f *fakeFacadeCaller) BestAPIVersion() int          { return 1 }
+func (f *fakeFacadeCaller) RawAPICaller() base.APICaller { return nil }
+
+// withFakeNotifyWatcherConstructor substitutes newNotifyWatcher with one
+// that returns fakeNotifyWatchers, returning a func that restores the
+// original; callers should defer the returned func.
+func withFakeNotifyWatcherConstructor() func() {
+	orig := newNotifyWatcher
+	newNotifyWatcher = func(base.APICaller, params.NotifyWatchResult) watcher.NotifyWatcher {
+		return newFakeNotifyWatcher()
+	}
+	return func() { newNotifyWatcher = orig }
+}
+
+type watchManySuite struct{}
+
+var _ = gc.Suite(&watchManySuite{})
+
+func (s *watchManySuite) TestWatchManyReportsPartialFailures(c *gc.C) {
+	defer withFakeNotifyWatcherConstructor()()
+
+	tag0 := names.NewUnitTag("mysql/0")
+	tag1 := names.NewUnitTag("mysql/1")
+	facade := &fakeFacadeCaller{
+		results: params.NotifyWatchResults{
+			Results: []params.NotifyWatchResult{
+				{NotifyWatcherId: "1"},
+				{Error: &params.Error{Message: "boom"}},
+			},
+		},
+	}
+
+	watchers, err := WatchMany(facade, "WatchUnits", []names.Tag{tag0, tag1})
+
+	multiErr, ok := err.(*MultiWatcherError)
+	c.Assert(ok, jc.IsTrue, gc.Commentf("expected a *MultiWatcherError, got %v (%T)", err, err))
+	c.Assert(multiErr.Total, gc.Equals, 2)
+	c.Assert(multiErr.Errors, gc.HasLen, 1)
+	c.Assert(multiErr.Errors[0].Tag, gc.Equals, tag1)
+	c.Assert(watchers, gc.HasLen, 2)
+	c.Assert(watchers[0], gc.NotNil, gc.Commentf("expected tag0 to still get a usable watcher"))
+	c.Assert(watchers[1], gc.IsNil, gc.Commentf("expected tag1's failed watch to leave a nil entry"))
+}
+
+func (s *watchManySuite) TestWatchManyAllSucceed(c *gc.C) {
+	defer withFakeNotifyWatcherConstructor()()
+
+	tag := names.NewUnitTag("mysql/0")
+	facade := &fakeFacadeCaller{
+		results: params.NotifyWatchResults{
+			Results: []params.NotifyWatchResult{{NotifyWatcherId: "1"}},
+		},
+	}
+
+	watchers, err := WatchMany(facade, "WatchUnits", []names.Tag{tag})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(watchers, gc.HasLen, 1)
+	c.Assert(watchers[0], gc.NotNil)
+}
+
+func (s *watchManySuite) TestWatchManyMismatchedResultCount(c *gc.C) {
+	defer withFakeNotifyWatcherConstructor()()
+
+	tag := names.NewUnitTag("mysql/0")
+	facade := &fakeFacadeCaller{results: params.NotifyWatchResults{}}
+
+	_, err := WatchMany(facade, "WatchUnits", []names.Tag{tag})
+	c.Assert(err, gc.NotNil)
+}
+
+type watchAggregatedSuite struct{}
+
+var _ = gc.Suite(&watchAggregatedSuite{})
+
+func (s *watchAggregatedSuite) TestWatchAggregatedCoalescesTicks(c *gc.C) {
+	a := newFakeNotifyWatcher()
+	b := newFakeNotifyWatcher()
+	agg, err := WatchAggregated(20*time.Millisecond, []watcher.NotifyWatcher{a, b})
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() {
+		agg.Kill()
+		agg.Wait()
+	}()
+
+	a.changes <- struct{}{}
+	b.changes <- struct{}{}
+
+	select {
+	case <-agg.Changes():
+	case <-time.After(time.Second):
+		c.Fatalf("timed out waiting for a coalesced tick")
+	}
+
+	select {
+	case <-agg.Changes():
+		c.Fatalf("received a second tick for changes that arrived within the same debounce window")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func (s *watchAggregatedSuite) TestWatchAggregatedKillStopsUnderlyingWatchers(c *gc.C) {
+	a := newFakeNotifyWatcher()
+	b := newFakeNotifyWatcher()
+	agg, err := WatchAggregated(20*time.Millisecond, []watcher.NotifyWatcher{a, b})
+	c.Assert(err, jc.ErrorIsNil)
+
+	agg.Kill()
+	c.Assert(agg.Wait(), jc.ErrorIsNil)
+
+	select {
+	case <-a.killed:
+	case <-time.After(time.Second):
+		c.Fatalf("killing the aggregated watcher did not propagate to watcher a")
+	}
+	select {
+	case <-b.killed:
+	case <-time.After(time.Second):
+		c.Fatalf("killing the aggregated watcher did not propagate to watcher b")
+	}
+}
+
+func (s *watchAggregatedSuite) TestWatchAggregatedPropagatesUnderlyingFailure(c *gc.C) {
+	a := newFakeNotifyWatcher()
+	a.err = errors.New("boom")
+	agg, err := WatchAggregated(20*time.Millisecond, []watcher.NotifyWatcher{a})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Simulate the underlying watcher dying on its own, e.g. because it
+	// failed to reconnect.
+	a.Kill()
+
+	err = agg.Wait()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
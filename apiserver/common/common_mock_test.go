@@ -60,3 +60,89 @@ func (mr *MockReloadSpacesStateMockRecorder) SaveSubnetsFromProvider(arg0, arg1
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSubnetsFromProvider", reflect.TypeOf((*MockReloadSpacesState)(nil).SaveSubnetsFromProvider), arg0, arg1)
 }
+
+// ListSpaces mocks base method
+func (m *MockReloadSpacesState) ListSpaces() ([]network.SpaceInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSpaces")
+	ret0, _ := ret[0].([]network.SpaceInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSpaces indicates an expected call of ListSpaces
+func (mr *MockReloadSpacesStateMockRecorder) ListSpaces() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSpaces", reflect.TypeOf((*MockReloadSpacesState)(nil).ListSpaces))
+}
+
+// RemoveSpaces mocks base method
+func (m *MockReloadSpacesState) RemoveSpaces(arg0 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveSpaces", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveSpaces indicates an expected call of RemoveSpaces
+func (mr *MockReloadSpacesStateMockRecorder) RemoveSpaces(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSpaces", reflect.TypeOf((*MockReloadSpacesState)(nil).RemoveSpaces), arg0)
+}
+
+// RenameSpace mocks base method
+func (m *MockReloadSpacesState) RenameSpace(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameSpace", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameSpace indicates an expected call of RenameSpace
+func (mr *MockReloadSpacesStateMockRecorder) RenameSpace(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameSpace", reflect.TypeOf((*MockReloadSpacesState)(nil).RenameSpace), arg0, arg1)
+}
+
+// ListSubnets mocks base method
+func (m *MockReloadSpacesState) ListSubnets() ([]network.SubnetInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubnets")
+	ret0, _ := ret[0].([]network.SubnetInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubnets indicates an expected call of ListSubnets
+func (mr *MockReloadSpacesStateMockRecorder) ListSubnets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubnets", reflect.TypeOf((*MockReloadSpacesState)(nil).ListSubnets))
+}
+
+// RemoveSubnets mocks base method
+func (m *MockReloadSpacesState) RemoveSubnets(arg0 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveSubnets", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveSubnets indicates an expected call of RemoveSubnets
+func (mr *MockReloadSpacesStateMockRecorder) RemoveSubnets(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSubnets", reflect.TypeOf((*MockReloadSpacesState)(nil).RemoveSubnets), arg0)
+}
+
+// RenameSubnet mocks base method
+func (m *MockReloadSpacesState) RenameSubnet(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameSubnet", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameSubnet indicates an expected call of RenameSubnet
+func (mr *MockReloadSpacesStateMockRecorder) RenameSubnet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameSubnet", reflect.TypeOf((*MockReloadSpacesState)(nil).RenameSubnet), arg0, arg1)
+}
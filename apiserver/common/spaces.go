@@ -11,6 +11,36 @@ import (
 	"github.com/juju/juju/environs/context"
 )
 
+// ErrSpaceInUse indicates that a space could not be removed because one or
+// more endpoint bindings still reference it.
+var ErrSpaceInUse = errors.New("space is in use by bindings")
+
+// ErrSubnetInUse indicates that a subnet could not be removed because one
+// or more endpoint bindings still reference it.
+var ErrSubnetInUse = errors.New("subnet is in use by bindings")
+
+// ReloadSpacesResult describes the effect that reconciling provider spaces
+// (or, where the provider cannot discover spaces, subnets) against state
+// had on the items already recorded there. Each list holds the provider
+// IDs of whichever of the two was reconciled.
+type ReloadSpacesResult struct {
+	// Added lists the provider IDs of items that were new to state.
+	Added []string
+
+	// Removed lists the provider IDs of items that the provider no
+	// longer reports, and that were successfully deleted.
+	Removed []string
+
+	// Renamed lists the provider IDs of items whose name (a space's Name,
+	// or a subnet's CIDR) changed.
+	Renamed []string
+
+	// SkippedRemovals lists the provider IDs of items that the provider
+	// no longer reports, but that were left in place because one or more
+	// endpoint bindings still reference them.
+	SkippedRemovals []string
+}
+
 // ReloadSpacesState defines an in situ point of use type for ReloadSpaces
 type ReloadSpacesState interface {
 	// SaveSpacesFromProvider loads providerSpaces into state.
@@ -18,32 +48,178 @@ type ReloadSpacesState interface {
 
 	// SaveSubnetsFromProvider loads subnets into state.
 	SaveSubnetsFromProvider([]network.SubnetInfo, string) error
+
+	// ListSpaces returns the spaces currently recorded in state.
+	ListSpaces() ([]network.SpaceInfo, error)
+
+	// RemoveSpaces deletes the spaces with the given provider IDs. It
+	// returns an error whose cause is ErrSpaceInUse if a space is still
+	// referenced by endpoint bindings.
+	RemoveSpaces([]string) error
+
+	// RenameSpace renames the space called old to new.
+	RenameSpace(old, new string) error
+
+	// ListSubnets returns the subnets currently recorded in state.
+	ListSubnets() ([]network.SubnetInfo, error)
+
+	// RemoveSubnets deletes the subnets with the given provider IDs. It
+	// returns an error whose cause is ErrSubnetInUse if a subnet is still
+	// referenced by endpoint bindings.
+	RemoveSubnets([]string) error
+
+	// RenameSubnet updates the CIDR recorded for the subnet whose CIDR is
+	// currently old to new, leaving its ProviderId unchanged.
+	RenameSubnet(old, new string) error
 }
 
-// ReloadSpaces loads spaces and subnets from provider specified by environ into state.
-// Currently it's an append-only operation, no spaces/subnets are deleted.
-func ReloadSpaces(ctx context.ProviderCallContext, state ReloadSpacesState, environ environs.BootstrapEnviron) error {
+// ReloadSpaces loads spaces (or, for providers that don't support space
+// discovery, subnets) from the provider specified by environ into state,
+// reconciling whichever of the two it loads against what's already in
+// state: items the provider no longer reports are removed (unless still
+// referenced by bindings, in which case they are skipped and noted in the
+// returned ReloadSpacesResult), items the provider newly reports are
+// added, and items whose ProviderId is unchanged but whose identifying
+// name (a space's Name, or a subnet's CIDR) differs are renamed rather
+// than dropped and recreated.
+func ReloadSpaces(ctx context.ProviderCallContext, state ReloadSpacesState, environ environs.BootstrapEnviron) (ReloadSpacesResult, error) {
 	netEnviron, ok := environs.SupportsNetworking(environ)
 	if !ok {
-		return errors.NotSupportedf("spaces discovery in a non-networking environ")
+		return ReloadSpacesResult{}, errors.NotSupportedf("spaces discovery in a non-networking environ")
 	}
 
 	canDiscoverSpaces, err := netEnviron.SupportsSpaceDiscovery(ctx)
 	if err != nil {
-		return errors.Trace(err)
+		return ReloadSpacesResult{}, errors.Trace(err)
 	}
 	if canDiscoverSpaces {
-		spaces, err := netEnviron.Spaces(ctx)
+		providerSpaces, err := netEnviron.Spaces(ctx)
 		if err != nil {
-			return errors.Trace(err)
+			return ReloadSpacesResult{}, errors.Trace(err)
 		}
-		return errors.Trace(state.SaveSpacesFromProvider(spaces))
+		return reconcileSpaces(state, providerSpaces)
 	}
 
 	logger.Debugf("environ does not support space discovery, falling back to subnet discovery")
-	subnets, err := netEnviron.Subnets(ctx, instance.UnknownId, nil)
+	providerSubnets, err := netEnviron.Subnets(ctx, instance.UnknownId, nil)
+	if err != nil {
+		return ReloadSpacesResult{}, errors.Trace(err)
+	}
+	return reconcileSubnets(state, providerSubnets)
+}
+
+// reconcileSpaces diffs providerSpaces against the spaces currently known
+// to state, adding, removing and renaming spaces as needed so that state
+// matches what the provider reports.
+func reconcileSpaces(state ReloadSpacesState, providerSpaces []network.SpaceInfo) (ReloadSpacesResult, error) {
+	var result ReloadSpacesResult
+
+	currentSpaces, err := state.ListSpaces()
 	if err != nil {
-		return errors.Trace(err)
+		return result, errors.Trace(err)
 	}
-	return errors.Trace(state.SaveSubnetsFromProvider(subnets, ""))
+	byProviderID := make(map[network.Id]network.SpaceInfo, len(currentSpaces))
+	for _, space := range currentSpaces {
+		byProviderID[space.ProviderId] = space
+	}
+
+	var toAdd []network.SpaceInfo
+	seen := make(map[network.Id]bool, len(providerSpaces))
+	for _, providerSpace := range providerSpaces {
+		seen[providerSpace.ProviderId] = true
+		current, known := byProviderID[providerSpace.ProviderId]
+		switch {
+		case !known:
+			toAdd = append(toAdd, providerSpace)
+		case current.Name != providerSpace.Name:
+			if err := state.RenameSpace(current.Name, providerSpace.Name); err != nil {
+				return result, errors.Trace(err)
+			}
+			result.Renamed = append(result.Renamed, string(providerSpace.ProviderId))
+		}
+	}
+	if len(toAdd) > 0 {
+		if err := state.SaveSpacesFromProvider(toAdd); err != nil {
+			return result, errors.Trace(err)
+		}
+		for _, space := range toAdd {
+			result.Added = append(result.Added, string(space.ProviderId))
+		}
+	}
+
+	for _, current := range currentSpaces {
+		if seen[current.ProviderId] {
+			continue
+		}
+		providerID := string(current.ProviderId)
+		if err := state.RemoveSpaces([]string{providerID}); err != nil {
+			if errors.Cause(err) == ErrSpaceInUse {
+				result.SkippedRemovals = append(result.SkippedRemovals, providerID)
+				continue
+			}
+			return result, errors.Trace(err)
+		}
+		result.Removed = append(result.Removed, providerID)
+	}
+
+	return result, nil
+}
+
+// reconcileSubnets diffs providerSubnets against the subnets currently
+// known to state, adding, removing and renaming subnets as needed so that
+// state matches what the provider reports. It mirrors reconcileSpaces,
+// using a subnet's CIDR as the identifying name that can change without
+// its ProviderId doing so.
+func reconcileSubnets(state ReloadSpacesState, providerSubnets []network.SubnetInfo) (ReloadSpacesResult, error) {
+	var result ReloadSpacesResult
+
+	currentSubnets, err := state.ListSubnets()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	byProviderID := make(map[network.Id]network.SubnetInfo, len(currentSubnets))
+	for _, subnet := range currentSubnets {
+		byProviderID[subnet.ProviderId] = subnet
+	}
+
+	var toAdd []network.SubnetInfo
+	seen := make(map[network.Id]bool, len(providerSubnets))
+	for _, providerSubnet := range providerSubnets {
+		seen[providerSubnet.ProviderId] = true
+		current, known := byProviderID[providerSubnet.ProviderId]
+		switch {
+		case !known:
+			toAdd = append(toAdd, providerSubnet)
+		case current.CIDR != providerSubnet.CIDR:
+			if err := state.RenameSubnet(current.CIDR, providerSubnet.CIDR); err != nil {
+				return result, errors.Trace(err)
+			}
+			result.Renamed = append(result.Renamed, string(providerSubnet.ProviderId))
+		}
+	}
+	if len(toAdd) > 0 {
+		if err := state.SaveSubnetsFromProvider(toAdd, ""); err != nil {
+			return result, errors.Trace(err)
+		}
+		for _, subnet := range toAdd {
+			result.Added = append(result.Added, string(subnet.ProviderId))
+		}
+	}
+
+	for _, current := range currentSubnets {
+		if seen[current.ProviderId] {
+			continue
+		}
+		providerID := string(current.ProviderId)
+		if err := state.RemoveSubnets([]string{providerID}); err != nil {
+			if errors.Cause(err) == ErrSubnetInUse {
+				result.SkippedRemovals = append(result.SkippedRemovals, providerID)
+				continue
+			}
+			return result, errors.Trace(err)
+		}
+		result.Removed = append(result.Removed, providerID)
+	}
+
+	return result, nil
 }
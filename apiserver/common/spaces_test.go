@@ -0,0 +1,141 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/network"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type reconcileSpacesSuite struct{}
+
+var _ = gc.Suite(&reconcileSpacesSuite{})
+
+func (s *reconcileSpacesSuite) TestReconcileSpacesAddsNewSpaces(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	state := NewMockReloadSpacesState(ctrl)
+	added := network.SpaceInfo{ProviderId: "provider-1", Name: "new-space"}
+	state.EXPECT().ListSpaces().Return(nil, nil)
+	state.EXPECT().SaveSpacesFromProvider([]network.SpaceInfo{added}).Return(nil)
+
+	result, err := reconcileSpaces(state, []network.SpaceInfo{added})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Added, gc.DeepEquals, []string{"provider-1"})
+}
+
+func (s *reconcileSpacesSuite) TestReconcileSpacesRemovesStaleSpaces(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	state := NewMockReloadSpacesState(ctrl)
+	stale := network.SpaceInfo{ProviderId: "provider-1", Name: "stale-space"}
+	state.EXPECT().ListSpaces().Return([]network.SpaceInfo{stale}, nil)
+	state.EXPECT().RemoveSpaces([]string{"provider-1"}).Return(nil)
+
+	result, err := reconcileSpaces(state, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Removed, gc.DeepEquals, []string{"provider-1"})
+}
+
+func (s *reconcileSpacesSuite) TestReconcileSpacesSkipsRemovalWhenSpaceInUse(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	state := NewMockReloadSpacesState(ctrl)
+	inUse := network.SpaceInfo{ProviderId: "provider-1", Name: "in-use-space"}
+	state.EXPECT().ListSpaces().Return([]network.SpaceInfo{inUse}, nil)
+	state.EXPECT().RemoveSpaces([]string{"provider-1"}).Return(errors.Trace(ErrSpaceInUse))
+
+	result, err := reconcileSpaces(state, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.SkippedRemovals, gc.DeepEquals, []string{"provider-1"})
+	c.Assert(result.Removed, gc.HasLen, 0)
+}
+
+func (s *reconcileSpacesSuite) TestReconcileSpacesRenamesOnProviderIDMatch(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	state := NewMockReloadSpacesState(ctrl)
+	current := network.SpaceInfo{ProviderId: "provider-1", Name: "old-name"}
+	renamed := network.SpaceInfo{ProviderId: "provider-1", Name: "new-name"}
+	state.EXPECT().ListSpaces().Return([]network.SpaceInfo{current}, nil)
+	state.EXPECT().RenameSpace("old-name", "new-name").Return(nil)
+
+	result, err := reconcileSpaces(state, []network.SpaceInfo{renamed})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Renamed, gc.DeepEquals, []string{"provider-1"})
+}
+
+type reconcileSubnetsSuite struct{}
+
+var _ = gc.Suite(&reconcileSubnetsSuite{})
+
+func (s *reconcileSubnetsSuite) TestReconcileSubnetsAddsNewSubnets(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	state := NewMockReloadSpacesState(ctrl)
+	added := network.SubnetInfo{ProviderId: "provider-1", CIDR: "10.0.0.0/24"}
+	state.EXPECT().ListSubnets().Return(nil, nil)
+	state.EXPECT().SaveSubnetsFromProvider([]network.SubnetInfo{added}, "").Return(nil)
+
+	result, err := reconcileSubnets(state, []network.SubnetInfo{added})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Added, gc.DeepEquals, []string{"provider-1"})
+}
+
+func (s *reconcileSubnetsSuite) TestReconcileSubnetsRemovesStaleSubnets(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	state := NewMockReloadSpacesState(ctrl)
+	stale := network.SubnetInfo{ProviderId: "provider-1", CIDR: "10.0.0.0/24"}
+	state.EXPECT().ListSubnets().Return([]network.SubnetInfo{stale}, nil)
+	state.EXPECT().RemoveSubnets([]string{"provider-1"}).Return(nil)
+
+	result, err := reconcileSubnets(state, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Removed, gc.DeepEquals, []string{"provider-1"})
+}
+
+func (s *reconcileSubnetsSuite) TestReconcileSubnetsSkipsRemovalWhenSubnetInUse(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	state := NewMockReloadSpacesState(ctrl)
+	inUse := network.SubnetInfo{ProviderId: "provider-1", CIDR: "10.0.0.0/24"}
+	state.EXPECT().ListSubnets().Return([]network.SubnetInfo{inUse}, nil)
+	state.EXPECT().RemoveSubnets([]string{"provider-1"}).Return(errors.Trace(ErrSubnetInUse))
+
+	result, err := reconcileSubnets(state, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.SkippedRemovals, gc.DeepEquals, []string{"provider-1"})
+	c.Assert(result.Removed, gc.HasLen, 0)
+}
+
+func (s *reconcileSubnetsSuite) TestReconcileSubnetsRenamesOnProviderIDMatch(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	state := NewMockReloadSpacesState(ctrl)
+	current := network.SubnetInfo{ProviderId: "provider-1", CIDR: "10.0.0.0/24"}
+	renamed := network.SubnetInfo{ProviderId: "provider-1", CIDR: "10.0.1.0/24"}
+	state.EXPECT().ListSubnets().Return([]network.SubnetInfo{current}, nil)
+	state.EXPECT().RenameSubnet("10.0.0.0/24", "10.0.1.0/24").Return(nil)
+
+	result, err := reconcileSubnets(state, []network.SubnetInfo{renamed})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Renamed, gc.DeepEquals, []string{"provider-1"})
+}
@@ -24,7 +24,10 @@
 package charmstore
 
 import (
+	"bytes"
+	"crypto/sha512"
 	"io"
+	"io/ioutil"
 
 	"github.com/juju/errors"
 	"github.com/juju/juju/core/model"
@@ -32,6 +35,10 @@ import (
 	"gopkg.in/juju/charmrepo.v3/csclient/params"
 )
 
+// maxResourceSize bounds how much of an io.Reader PutReader will buffer, so
+// that a caller can't make it try to hold an unbounded blob in memory.
+const maxResourceSize = 500 * 1024 * 1024
+
 // datastore is a small, in-memory key/value store. Its primary use case is to
 // fake HTTP calls.
 //
@@ -65,12 +72,11 @@ func (d datastore) Put(path string, data interface{}) error {
 // Data already at path will is overwritten and no
 // revision history is saved.
 func (d *datastore) PutReader(path string, data io.Reader) error {
-	buffer := []byte{}
-	_, err := data.Read(buffer)
-	if err != nil {
+	var buffer bytes.Buffer
+	if _, err := io.Copy(&buffer, io.LimitReader(data, maxResourceSize)); err != nil {
 		return errors.Trace(err)
 	}
-	return d.Put(path, buffer)
+	return d.Put(path, buffer.Bytes())
 }
 
 // FakeClient is a stand-in for the gopkg.in/juju/charmrepo.v3/csclient Client type.
@@ -107,6 +113,43 @@ func (c FakeClient) WithChannel(channel params.Channel) *ChannelAwareFakeClient
 	return &ChannelAwareFakeClient{channel, c}
 }
 
+// UploadCharm stores withRevision as the next revision of id.
+func (c FakeClient) UploadCharm(id *charm.URL, withRevision charm.Charm) (*charm.URL, error) {
+	return c.repo.UploadCharm(id, withRevision)
+}
+
+// Publish makes an already-uploaded revision of id available on each of
+// channels directly.
+func (c FakeClient) Publish(id *charm.URL, channels []params.Channel) error {
+	return c.repo.Publish(id, channels)
+}
+
+// Release promotes the revision of id currently resolved on the from
+// channel onto the to channel.
+func (c FakeClient) Release(id *charm.URL, from, to params.Channel) error {
+	return c.repo.Release(id, from, to)
+}
+
+// UploadResource stores blob as the next revision of the named resource
+// (meta.Name) attached to id, returning the revision number allocated to
+// it.
+func (c FakeClient) UploadResource(id *charm.URL, meta params.Resource, blob io.Reader) (int, error) {
+	return c.repo.UploadResource(id, meta, blob)
+}
+
+// ListResources returns the resources currently visible for id on the
+// underlying repository's channel.
+func (c FakeClient) ListResources(id *charm.URL) ([]params.Resource, error) {
+	return c.repo.ListResources(id)
+}
+
+// ResourceInfo returns the metadata and content for revision rev of the
+// named resource attached to id, provided that revision is visible on the
+// underlying repository's channel.
+func (c FakeClient) ResourceInfo(id *charm.URL, name string, rev int) (params.Resource, io.ReadCloser, error) {
+	return c.repo.ResourceInfo(id, name, rev)
+}
+
 // ChannelAwareFakeClient is a charmstore client that stores the channel that its methods
 // refer to across calls. That is, it is stateful. It is modelled on the Client type defined in
 // gopkg.in/juju/charmrepo.v3/csclient.
@@ -134,6 +177,11 @@ func (c ChannelAwareFakeClient) WithChannel(channel params.Channel) *ChannelAwar
 	return &c
 }
 
+// Release promotes id from the client's current channel onto to.
+func (c ChannelAwareFakeClient) Release(id *charm.URL, to params.Channel) error {
+	return c.charmstore.Release(id, c.channel, to)
+}
+
 // Repository provides in-memory access to charms and other objects
 // held in a charmstore (or locally), such as bundles and resources.
 // Its intended use case is to act as a fake charmrepo for testing purposes.
@@ -158,6 +206,36 @@ type Repository struct {
 	added         map[string][]charm.URL
 	resourcesData datastore
 	generations   map[model.GenerationVersion]string
+
+	// blobs holds the content of every uploaded charm revision, keyed by
+	// its fully resolved URL (including revision), independently of which
+	// channels that revision has since been released to.
+	blobs map[charm.URL]charm.Charm
+
+	// nextRevision tracks the next revision number to hand out per charm,
+	// keyed by the charm's URL with its revision stripped.
+	nextRevision map[charm.URL]int
+
+	// resourceRevisions tracks the next revision number to hand out per
+	// charm and resource name.
+	resourceRevisions map[charm.URL]map[string]int
+
+	// resourceMetas holds the metadata of every uploaded resource
+	// revision, independently of which channels it has since been
+	// released to.
+	resourceMetas map[resourceKey]params.Resource
+
+	// resourceBlobs holds the content of every uploaded resource
+	// revision.
+	resourceBlobs map[resourceKey][]byte
+}
+
+// resourceKey identifies a single revision of a named resource attached to
+// a charm.
+type resourceKey struct {
+	charm    charm.URL
+	name     string
+	revision int
 }
 
 // NewRepository returns an empty Repository. To populate it with charms, bundles and resources
@@ -171,6 +249,12 @@ func NewRepository() *Repository {
 		revisions:     make(map[params.Channel]map[charm.URL]int),
 		added:         make(map[string][]charm.URL),
 		resourcesData: make(datastore),
+		blobs:         make(map[charm.URL]charm.Charm),
+		nextRevision:  make(map[charm.URL]int),
+
+		resourceRevisions: make(map[charm.URL]map[string]int),
+		resourceMetas:     make(map[resourceKey]params.Resource),
+		resourceBlobs:     make(map[resourceKey][]byte),
 	}
 	for _, channel := range params.OrderedChannels {
 		repo.charms[channel] = make(map[charm.URL]charm.Charm)
@@ -186,6 +270,193 @@ func (r *Repository) addRevision(ref *charm.URL) *charm.URL {
 	return ref.WithRevision(revision)
 }
 
+// baseKey returns ref with its revision stripped, used to key Repository
+// state that spans every revision and channel of a single charm.
+func baseKey(ref *charm.URL) charm.URL {
+	return *ref.WithRevision(-1)
+}
+
+// isKnownChannel reports whether channel is one of params.OrderedChannels,
+// i.e. one of the channels Repository actually allocates storage for.
+// Writing to r.charms/r.revisions/r.resources for any other channel value
+// would panic with "assignment to entry in nil map".
+func isKnownChannel(channel params.Channel) bool {
+	for _, known := range params.OrderedChannels {
+		if channel == known {
+			return true
+		}
+	}
+	return false
+}
+
+// UploadCharm stores ch as the next revision of id and releases it onto
+// params.EdgeChannel. Every upload lands on edge first; promoting it
+// further onto beta, candidate or stable requires an explicit Release.
+func (r *Repository) UploadCharm(id *charm.URL, ch charm.Charm) (*charm.URL, error) {
+	key := baseKey(id)
+	revision := r.nextRevision[key]
+	r.nextRevision[key] = revision + 1
+
+	withRevision := id.WithRevision(revision)
+	r.blobs[*withRevision] = ch
+	r.charms[params.EdgeChannel][*withRevision] = ch
+	r.revisions[params.EdgeChannel][key] = revision
+	return withRevision, nil
+}
+
+// Publish makes the already-uploaded revision id (which must include a
+// revision number, as returned by UploadCharm) available on each of
+// channels directly, without requiring it to already be released
+// elsewhere. It is the fake equivalent of the legacy charmstore "publish"
+// verb.
+//
+// Publishing directly to params.StableChannel is rejected: stable can only
+// be reached via Release.
+func (r *Repository) Publish(id *charm.URL, channels []params.Channel) error {
+	ch, ok := r.blobs[*id]
+	if !ok {
+		return errors.NotFoundf("charm %q", id)
+	}
+	for _, channel := range channels {
+		if !isKnownChannel(channel) {
+			return errors.NotValidf("channel %q", channel)
+		}
+		if channel == params.StableChannel {
+			return errors.Errorf("cannot publish %q directly to stable channel: release it instead", id)
+		}
+	}
+	key := baseKey(id)
+	for _, channel := range channels {
+		r.charms[channel][*id] = ch
+		r.revisions[channel][key] = id.Revision
+		r.snapshotResources(key, params.EdgeChannel, channel)
+	}
+	return nil
+}
+
+// Release promotes the revision of id currently resolved on the from
+// channel onto the to channel.
+func (r *Repository) Release(id *charm.URL, from, to params.Channel) error {
+	if !isKnownChannel(from) {
+		return errors.NotValidf("channel %q", from)
+	}
+	if !isKnownChannel(to) {
+		return errors.NotValidf("channel %q", to)
+	}
+	key := baseKey(id)
+	revision, ok := r.revisions[from][key]
+	if !ok {
+		return errors.NotFoundf("charm %q on channel %q", id, from)
+	}
+	withRevision := *key.WithRevision(revision)
+	ch, ok := r.charms[from][withRevision]
+	if !ok {
+		return errors.NotFoundf("charm %q on channel %q", id, from)
+	}
+	r.charms[to][withRevision] = ch
+	r.revisions[to][key] = revision
+	r.snapshotResources(key, from, to)
+	return nil
+}
+
+// snapshotResources copies the resource list currently visible on from
+// onto to, by value. Resources are only ever uploaded onto
+// params.EdgeChannel, so this snapshot is what stops a later
+// UploadResource from mutating what a charm already released to, say,
+// stable exposes.
+func (r *Repository) snapshotResources(key charm.URL, from, to params.Channel) {
+	current := r.resources[from][key]
+	if len(current) == 0 {
+		return
+	}
+	snapshot := make([]params.Resource, len(current))
+	copy(snapshot, current)
+	r.resources[to][key] = snapshot
+}
+
+// setChannelResource records meta as the currently visible revision of its
+// named resource for key on channel, replacing any previous entry for the
+// same name.
+func (r *Repository) setChannelResource(channel params.Channel, key charm.URL, meta params.Resource) {
+	list := r.resources[channel][key]
+	for i, existing := range list {
+		if existing.Name == meta.Name {
+			list[i] = meta
+			return
+		}
+	}
+	r.resources[channel][key] = append(list, meta)
+}
+
+// UploadResource stores blob as the next revision of the named resource
+// (meta.Name) attached to id, filling in meta's Size and Fingerprint from
+// its content. Like UploadCharm, the resource is attached to
+// params.EdgeChannel; it reaches other channels only when the charm it
+// belongs to is Published or Released there, which snapshots the
+// resource's current revision onto that channel (see snapshotResources).
+func (r *Repository) UploadResource(id *charm.URL, meta params.Resource, blob io.Reader) (int, error) {
+	content, err := ioutil.ReadAll(blob)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+
+	key := baseKey(id)
+	if r.resourceRevisions[key] == nil {
+		r.resourceRevisions[key] = make(map[string]int)
+	}
+	revision := r.resourceRevisions[key][meta.Name]
+	r.resourceRevisions[key][meta.Name] = revision + 1
+
+	meta.Revision = revision
+	meta.Size = int64(len(content))
+	fingerprint := sha512.Sum384(content)
+	meta.Fingerprint = fingerprint[:]
+
+	rk := resourceKey{charm: key, name: meta.Name, revision: revision}
+	r.resourceMetas[rk] = meta
+	r.resourceBlobs[rk] = content
+	r.setChannelResource(params.EdgeChannel, key, meta)
+	return revision, nil
+}
+
+// ListResources returns the resources currently visible for id on the
+// repository's channel. A charm with no resources attached returns an
+// empty slice and a nil error: callers that treat errors.IsNotFound as "no
+// such charm or channel" must not see that for the ordinary case of a
+// charm that simply has zero resources.
+func (r *Repository) ListResources(id *charm.URL) ([]params.Resource, error) {
+	key := baseKey(id)
+	current := r.resources[r.channel][key]
+	out := make([]params.Resource, len(current))
+	copy(out, current)
+	return out, nil
+}
+
+// ResourceInfo returns the metadata and content for revision rev of the
+// named resource attached to id, provided that revision is the one
+// currently visible on the repository's channel.
+func (r *Repository) ResourceInfo(id *charm.URL, name string, rev int) (params.Resource, io.ReadCloser, error) {
+	key := baseKey(id)
+	visible := false
+	for _, meta := range r.resources[r.channel][key] {
+		if meta.Name == name && meta.Revision == rev {
+			visible = true
+			break
+		}
+	}
+	if !visible {
+		return params.Resource{}, nil, errors.NotFoundf("resource %q revision %d of charm %q on channel %q", name, rev, id, r.channel)
+	}
+
+	rk := resourceKey{charm: key, name: name, revision: rev}
+	meta, ok := r.resourceMetas[rk]
+	content, hasContent := r.resourceBlobs[rk]
+	if !ok || !hasContent {
+		return params.Resource{}, nil, errors.NotFoundf("resource %q revision %d of charm %q", name, rev, id)
+	}
+	return meta, ioutil.NopCloser(bytes.NewReader(content)), nil
+}
+
 // Resolve disambiguates a charm to a specific revision.
 //
 // Part of the charmrepo.Interface
@@ -0,0 +1,127 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmstore
+
+import (
+	"testing"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/charmrepo.v3/csclient/params"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+// fakeCharm is the minimal charm.Charm implementation needed to exercise
+// Repository's channel/promotion bookkeeping; its content is never
+// inspected by Repository itself.
+type fakeCharm struct{}
+
+func (fakeCharm) Meta() *charm.Meta       { return &charm.Meta{Name: "mysql"} }
+func (fakeCharm) Config() *charm.Config   { return &charm.Config{} }
+func (fakeCharm) Metrics() *charm.Metrics { return &charm.Metrics{} }
+func (fakeCharm) Actions() *charm.Actions { return &charm.Actions{} }
+func (fakeCharm) Revision() int           { return 0 }
+
+type repositorySuite struct{}
+
+var _ = gc.Suite(&repositorySuite{})
+
+func (s *repositorySuite) TestPromotionEdgeToStable(c *gc.C) {
+	repo := NewRepository()
+	id := charm.MustParseURL("cs:focal/mysql")
+
+	withRev, err := repo.UploadCharm(id, fakeCharm{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	repo.channel = params.EdgeChannel
+	_, err = repo.Get(withRev)
+	c.Assert(err, jc.ErrorIsNil)
+
+	for _, ch := range []params.Channel{params.BetaChannel, params.CandidateChannel, params.StableChannel} {
+		repo.channel = ch
+		_, err := repo.Get(withRev)
+		c.Assert(err, gc.NotNil, gc.Commentf("Get on %q unexpectedly succeeded before any release", ch))
+	}
+
+	err = repo.Release(id, params.EdgeChannel, params.BetaChannel)
+	c.Assert(err, jc.ErrorIsNil)
+	repo.channel = params.BetaChannel
+	_, err = repo.Get(withRev)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = repo.Release(id, params.BetaChannel, params.CandidateChannel)
+	c.Assert(err, jc.ErrorIsNil)
+	repo.channel = params.CandidateChannel
+	_, err = repo.Get(withRev)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = repo.Release(id, params.CandidateChannel, params.StableChannel)
+	c.Assert(err, jc.ErrorIsNil)
+	repo.channel = params.StableChannel
+	_, err = repo.Get(withRev)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *repositorySuite) TestDemoteAndRedistributeRevisions(c *gc.C) {
+	repo := NewRepository()
+	id := charm.MustParseURL("cs:focal/mysql")
+
+	rev0, err := repo.UploadCharm(id, fakeCharm{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = repo.Release(id, params.EdgeChannel, params.StableChannel)
+	c.Assert(err, jc.ErrorIsNil)
+
+	rev1, err := repo.UploadCharm(id, fakeCharm{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = repo.Release(id, params.EdgeChannel, params.BetaChannel)
+	c.Assert(err, jc.ErrorIsNil)
+
+	repo.channel = params.StableChannel
+	_, err = repo.Get(rev0)
+	c.Assert(err, jc.ErrorIsNil)
+	repo.channel = params.BetaChannel
+	_, err = repo.Get(rev1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Demote: redistribute the older, stable revision back onto beta,
+	// overwriting the newer revision that beta had.
+	err = repo.Release(id, params.StableChannel, params.BetaChannel)
+	c.Assert(err, jc.ErrorIsNil)
+	repo.channel = params.BetaChannel
+	_, err = repo.Get(rev0)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *repositorySuite) TestPublishRejectsStableChannel(c *gc.C) {
+	repo := NewRepository()
+	id := charm.MustParseURL("cs:focal/mysql")
+	withRev, err := repo.UploadCharm(id, fakeCharm{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = repo.Publish(withRev, []params.Channel{params.StableChannel})
+	c.Assert(err, gc.NotNil)
+}
+
+func (s *repositorySuite) TestPublishRejectsUnknownChannel(c *gc.C) {
+	repo := NewRepository()
+	id := charm.MustParseURL("cs:focal/mysql")
+	withRev, err := repo.UploadCharm(id, fakeCharm{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = repo.Publish(withRev, []params.Channel{params.Channel("bogus")})
+	c.Assert(errors.IsNotValid(err), jc.IsTrue)
+}
+
+func (s *repositorySuite) TestReleaseRejectsUnknownChannel(c *gc.C) {
+	repo := NewRepository()
+	id := charm.MustParseURL("cs:focal/mysql")
+	_, err := repo.UploadCharm(id, fakeCharm{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = repo.Release(id, params.EdgeChannel, params.Channel("bogus"))
+	c.Assert(errors.IsNotValid(err), jc.IsTrue)
+}
@@ -0,0 +1,93 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmstore
+
+import (
+	"bytes"
+	"io/ioutil"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/charmrepo.v3/csclient/params"
+)
+
+type resourcesSuite struct{}
+
+var _ = gc.Suite(&resourcesSuite{})
+
+func (s *resourcesSuite) TestListResourcesEmptyReturnsNilNotError(c *gc.C) {
+	repo := NewRepository()
+	id := charm.MustParseURL("cs:focal/mysql")
+	withRev, err := repo.UploadCharm(id, fakeCharm{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	repo.channel = params.EdgeChannel
+	resources, err := repo.ListResources(withRev)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources, gc.HasLen, 0)
+}
+
+func (s *resourcesSuite) TestUploadResourceMultipleRevisions(c *gc.C) {
+	repo := NewRepository()
+	id := charm.MustParseURL("cs:focal/mysql")
+	withRev, err := repo.UploadCharm(id, fakeCharm{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	rev0, err := repo.UploadResource(withRev, params.Resource{Name: "data"}, bytes.NewReader([]byte("v0")))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rev0, gc.Equals, 0)
+
+	rev1, err := repo.UploadResource(withRev, params.Resource{Name: "data"}, bytes.NewReader([]byte("v1, longer")))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rev1, gc.Equals, 1)
+
+	repo.channel = params.EdgeChannel
+	resources, err := repo.ListResources(withRev)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources, gc.HasLen, 1)
+	c.Assert(resources[0].Revision, gc.Equals, rev1)
+
+	_, _, err = repo.ResourceInfo(withRev, "data", rev0)
+	c.Assert(err, gc.NotNil, gc.Commentf("ResourceInfo for the superseded revision unexpectedly succeeded"))
+
+	meta, content, err := repo.ResourceInfo(withRev, "data", rev1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta.Size, gc.Equals, int64(len("v1, longer")))
+	c.Assert(len(meta.Fingerprint) > 0, jc.IsTrue)
+
+	got, err := ioutil.ReadAll(content)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(got), gc.Equals, "v1, longer")
+}
+
+func (s *resourcesSuite) TestResourcesAreIsolatedByChannel(c *gc.C) {
+	repo := NewRepository()
+	id := charm.MustParseURL("cs:focal/mysql")
+	withRev, err := repo.UploadCharm(id, fakeCharm{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = repo.UploadResource(withRev, params.Resource{Name: "data"}, bytes.NewReader([]byte("stable content")))
+	c.Assert(err, jc.ErrorIsNil)
+	err = repo.Release(id, params.EdgeChannel, params.StableChannel)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A later resource revision uploaded to edge must not be visible on
+	// stable, since stable only ever sees the snapshot taken at release
+	// time.
+	_, err = repo.UploadResource(withRev, params.Resource{Name: "data"}, bytes.NewReader([]byte("newer edge content")))
+	c.Assert(err, jc.ErrorIsNil)
+
+	repo.channel = params.StableChannel
+	stableResources, err := repo.ListResources(withRev)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stableResources, gc.HasLen, 1)
+	c.Assert(stableResources[0].Revision, gc.Equals, 0)
+
+	repo.channel = params.EdgeChannel
+	edgeResources, err := repo.ListResources(withRev)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(edgeResources, gc.HasLen, 1)
+	c.Assert(edgeResources[0].Revision, gc.Equals, 1)
+}